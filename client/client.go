@@ -0,0 +1,178 @@
+// Package client provides a NATS client for calling itsy services.
+// It mirrors the topology-aware subject layout used by [itsy.Service] so
+// callers don't have to hand-build subjects or parse response headers.
+package client
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// Options for a new Client.
+type Options struct {
+	Prefix string // Service prefix, which defaults to "svc"
+
+	// Topologies is the preferred topology search order for unscoped calls,
+	// e.g. []string{"eu.nl.ams"}. The first entry is used for [Client.Call]
+	// and [Client.Broadcast] unless overridden with [WithTopology].
+	Topologies []string
+
+	NC      *nats.Conn    // NATS connection to use (required)
+	Logger  *slog.Logger  // defaults to slog.Default()
+	Timeout time.Duration // default request timeout, defaults to 5s
+}
+
+// Client calls itsy services over NATS.
+type Client struct {
+	opt Options
+}
+
+// New creates a new Client.
+func New(opt Options) (*Client, error) {
+	if opt.NC == nil {
+		return nil, errors.New("nc option is required")
+	}
+	if opt.Prefix == "" {
+		opt.Prefix = "svc"
+	}
+	if opt.Logger == nil {
+		opt.Logger = slog.Default().With("component", "itsy-client")
+	}
+	if opt.Timeout == 0 {
+		opt.Timeout = 5 * time.Second
+	}
+	return &Client{opt: opt}, nil
+}
+
+// callConfig holds the per-call overrides applied by [CallOption]s.
+type callConfig struct {
+	timeout  time.Duration
+	topology string
+}
+
+// CallOption customizes a single [Client.Call], [Client.CallID] or
+// [Client.Broadcast] invocation.
+type CallOption func(*callConfig)
+
+// WithTimeout overrides the client's default timeout for this call.
+func WithTimeout(d time.Duration) CallOption {
+	return func(c *callConfig) { c.timeout = d }
+}
+
+// WithTopology overrides the client's preferred topology for this call,
+// e.g. "eu.nl" to scope a broadcast to a single country.
+func WithTopology(topo string) CallOption {
+	return func(c *callConfig) { c.topology = topo }
+}
+
+func (c *Client) newConfig(opts []CallOption) callConfig {
+	cfg := callConfig{timeout: c.opt.Timeout}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+func (c *Client) preferredTopology(cfg callConfig) string {
+	if cfg.topology != "" {
+		return cfg.topology
+	}
+	if len(c.opt.Topologies) > 0 {
+		return c.opt.Topologies[0]
+	}
+	return ""
+}
+
+// joinSubject joins non-empty parts with ".".
+func joinSubject(parts ...string) string {
+	var nonEmpty []string
+	for _, p := range parts {
+		if p != "" {
+			nonEmpty = append(nonEmpty, p)
+		}
+	}
+	return strings.Join(nonEmpty, ".")
+}
+
+// Call sends a unicast request to a single instance of service, chosen by
+// NATS among those registered under the client's preferred topology, e.g.
+// "<prefix>.<service>.<endpoint>.any.eu.nl.ams".
+func (c *Client) Call(ctx context.Context, service, endpoint string, data []byte, opts ...CallOption) (*Response, error) {
+	cfg := c.newConfig(opts)
+	subject := joinSubject(c.opt.Prefix, service, endpoint, "any", c.preferredTopology(cfg))
+	return c.request(ctx, subject, data, cfg)
+}
+
+// CallID sends a request pinned to a specific service instance, e.g.
+// "<prefix>.<service>.<endpoint>.id.<svcID>".
+func (c *Client) CallID(ctx context.Context, service, endpoint, svcID string, data []byte, opts ...CallOption) (*Response, error) {
+	if svcID == "" {
+		return nil, errors.New("svcID is required")
+	}
+	cfg := c.newConfig(opts)
+	subject := joinSubject(c.opt.Prefix, service, endpoint, "id", svcID)
+	return c.request(ctx, subject, data, cfg)
+}
+
+func (c *Client) request(ctx context.Context, subject string, data []byte, cfg callConfig) (*Response, error) {
+	ctx, cancel := context.WithTimeout(ctx, cfg.timeout)
+	defer cancel()
+	msg, err := c.opt.NC.RequestWithContext(ctx, subject, data)
+	if err != nil {
+		return nil, err
+	}
+	return parseResponse(msg)
+}
+
+// Broadcast publishes a scoped request, e.g.
+// "<prefix>.<service>.<endpoint>.all.eu.nl", and collects responses from
+// every instance that answers. It returns once expected responses have been
+// collected, the call's timeout elapses, or ctx is done - whichever happens
+// first. A non-positive expected disables the count check, so Broadcast
+// always runs until the timeout or ctx is done.
+func (c *Client) Broadcast(ctx context.Context, service, endpoint string, data []byte, expected int, collect func(*Response), opts ...CallOption) error {
+	cfg := c.newConfig(opts)
+	subject := joinSubject(c.opt.Prefix, service, endpoint, "all", c.preferredTopology(cfg))
+
+	inbox := nats.NewInbox()
+	sub, err := c.opt.NC.SubscribeSync(inbox)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = sub.Unsubscribe() }()
+
+	msg := nats.NewMsg(subject)
+	msg.Data = data
+	msg.Reply = inbox
+	if err := c.opt.NC.PublishMsg(msg); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.timeout)
+	defer cancel()
+
+	count := 0
+	for {
+		reply, err := sub.NextMsgWithContext(ctx)
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, nats.ErrTimeout) {
+				return nil
+			}
+			return err
+		}
+		// parseResponse's only error is *ErrorResponse, for a legitimate
+		// micro error reply (e.g. a replica returning BAD_REQUEST); resp is
+		// always populated, so it's delivered to collect either way.
+		resp, _ := parseResponse(reply)
+		collect(resp)
+		count++
+		if expected > 0 && count >= expected {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/PowerDNS/itsy"
+	"github.com/PowerDNS/itsy/client"
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+func ExampleClient_Call() {
+	// Start an in-process NATS server for this example
+	server, err := natsserver.NewServer(&natsserver.Options{
+		DontListen: true,
+	})
+	check(err)
+	server.Start()
+	defer server.Shutdown()
+	if !server.ReadyForConnections(time.Second * 5) {
+		panic("NATS server didn't start")
+	}
+
+	conf := itsy.Config{
+		Prefix: "test-itsy",
+		Topologies: []string{
+			"eu.nl.ams",
+		},
+	}
+	s, err := itsy.Start(itsy.Options{
+		Config:      conf,
+		Name:        "itsy-example",
+		Description: "An example service",
+		ConnectOptions: []nats.Option{
+			nats.InProcessServer(server),
+		},
+	})
+	check(err)
+	defer s.Stop()
+
+	s.MustAddHandler("echo", func(req itsy.Request) error {
+		return req.Respond(req.Data())
+	}, nil)
+
+	nc, err := nats.Connect("", nats.InProcessServer(server))
+	check(err)
+	defer nc.Close()
+
+	c, err := client.New(client.Options{
+		Prefix:     "test-itsy",
+		Topologies: []string{"eu.nl.ams"},
+		NC:         nc,
+	})
+	check(err)
+
+	resp, err := c.Call(context.Background(), "itsy-example", "echo", []byte("hello world"))
+	check(err)
+	fmt.Println("Received:", string(resp.Data))
+	fmt.Println("From:", resp.ServiceName)
+
+	// Output:
+	// Received: hello world
+	// From: itsy-example
+}
+
+func check(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
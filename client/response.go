@@ -0,0 +1,61 @@
+package client
+
+import (
+	"errors"
+
+	"github.com/nats-io/nats.go"
+)
+
+// Response is a reply from an itsy service, with the common headers itsy
+// services set on every response (see Service.start in the itsy package)
+// parsed into fields.
+type Response struct {
+	ServiceID       string // from the Service-ID header
+	ServiceName     string // from the Service-Name header
+	ServiceVersion  string // from the Service-Version header
+	ServiceTopology string // from the Service-Topology header
+	ServiceHostname string // from the Service-Hostname header, if set
+
+	Data    []byte      // response payload
+	Headers nats.Header // all response headers, including the ones above
+}
+
+// ErrorResponse is returned by [parseResponse] when the reply carries a
+// NATS micro error (the "Nats-Service-Error" / "Nats-Service-Error-Code"
+// headers set by micro.Request.Error).
+type ErrorResponse struct {
+	Code        string
+	Description string
+}
+
+func (e *ErrorResponse) Error() string {
+	return e.Description
+}
+
+func parseResponse(msg *nats.Msg) (*Response, error) {
+	h := msg.Header
+	resp := &Response{
+		ServiceID:       h.Get("Service-ID"),
+		ServiceName:     h.Get("Service-Name"),
+		ServiceVersion:  h.Get("Service-Version"),
+		ServiceTopology: h.Get("Service-Topology"),
+		ServiceHostname: h.Get("Service-Hostname"),
+		Data:            msg.Data,
+		Headers:         h,
+	}
+	if code := h.Get("Nats-Service-Error-Code"); code != "" {
+		return resp, &ErrorResponse{
+			Code:        code,
+			Description: h.Get("Nats-Service-Error"),
+		}
+	}
+	return resp, nil
+}
+
+// AsErrorResponse is a convenience wrapper around errors.As for callers that
+// want to inspect the error code of a failed call.
+func AsErrorResponse(err error) (*ErrorResponse, bool) {
+	var er *ErrorResponse
+	ok := errors.As(err, &er)
+	return er, ok
+}
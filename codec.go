@@ -0,0 +1,111 @@
+package itsy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/nats-io/nats.go/micro"
+)
+
+// Codec marshals and unmarshals the payload used by [Typed] handlers.
+type Codec interface {
+	// ContentType is advertised in the response's Content-Type header.
+	ContentType() string
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// headerAwareCodec is implemented by codecs, such as [NegotiatingCodec],
+// that need to pick a concrete [Codec] based on the request's headers.
+type headerAwareCodec interface {
+	Codec
+	ForRequest(headers micro.Headers) Codec
+}
+
+// JSONCodec marshals and unmarshals payloads as JSON.
+type JSONCodec struct{}
+
+func (JSONCodec) ContentType() string { return "application/json" }
+
+func (JSONCodec) Marshal(v any) ([]byte, error) { return json.Marshal(v) }
+
+func (JSONCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+// NegotiatingCodec picks a [Codec] at runtime based on the request's
+// Content-Type and Accept headers, falling back to the first codec in
+// Codecs if neither header matches one it knows.
+type NegotiatingCodec struct {
+	Codecs []Codec
+}
+
+// NewNegotiatingCodec returns a NegotiatingCodec over codecs. The first
+// codec is used as the default when no header matches.
+func NewNegotiatingCodec(codecs ...Codec) *NegotiatingCodec {
+	return &NegotiatingCodec{Codecs: codecs}
+}
+
+func (n *NegotiatingCodec) ContentType() string { return n.Codecs[0].ContentType() }
+
+func (n *NegotiatingCodec) Marshal(v any) ([]byte, error) { return n.Codecs[0].Marshal(v) }
+
+func (n *NegotiatingCodec) Unmarshal(data []byte, v any) error { return n.Codecs[0].Unmarshal(data, v) }
+
+// ForRequest picks the codec to use for a given request: the Accept header
+// wins (it names what the caller wants back), then Content-Type (it names
+// what the caller sent), then the default.
+func (n *NegotiatingCodec) ForRequest(headers micro.Headers) Codec {
+	if c := n.byContentType(headers.Get("Accept")); c != nil {
+		return c
+	}
+	if c := n.byContentType(headers.Get("Content-Type")); c != nil {
+		return c
+	}
+	return n.Codecs[0]
+}
+
+func (n *NegotiatingCodec) byContentType(ct string) Codec {
+	if ct == "" {
+		return nil
+	}
+	for _, c := range n.Codecs {
+		if c.ContentType() == ct {
+			return c
+		}
+	}
+	return nil
+}
+
+// Typed wraps fn, a handler that works with decoded request/response values
+// instead of raw bytes, into a HandlerFunc. Request data is decoded into Req
+// using codec (or, for a [headerAwareCodec] such as NegotiatingCodec, the
+// codec it picks for this request); the returned Resp is encoded the same
+// way and sent as the response, with a Content-Type header set to the
+// codec's ContentType. Decode errors are turned into a BAD_REQUEST
+// [ErrorResponse] automatically, so fn never sees a malformed request. fn is
+// called with r.Context(), so a context attached by a middleware (e.g. a
+// tracing span) reaches it.
+func Typed[Req, Resp any](fn func(ctx context.Context, r Request, in Req) (Resp, error), codec Codec) HandlerFunc {
+	return func(req Request) error {
+		c := codec
+		if hc, ok := codec.(headerAwareCodec); ok {
+			c = hc.ForRequest(req.Headers())
+		}
+
+		var in Req
+		if err := c.Unmarshal(req.Data(), &in); err != nil {
+			return Wrap(fmt.Errorf("decoding request: %w", err), "BAD_REQUEST")
+		}
+
+		out, err := fn(req.Context(), req, in)
+		if err != nil {
+			return err
+		}
+
+		data, err := c.Marshal(out)
+		if err != nil {
+			return err
+		}
+		return req.Respond(data, micro.WithHeaders(micro.Headers{"Content-Type": {c.ContentType()}}))
+	}
+}
@@ -0,0 +1,68 @@
+package itsy_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/PowerDNS/itsy"
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiatingCodec_ForRequest(t *testing.T) {
+	json := itsy.JSONCodec{}
+	n := itsy.NewNegotiatingCodec(json, itsy.ProtoCodec{})
+
+	assert.Equal(t, "application/json", n.ContentType())
+
+	c := n.ForRequest(map[string][]string{"Accept": {"application/x-protobuf"}})
+	assert.Equal(t, "application/x-protobuf", c.ContentType())
+
+	c = n.ForRequest(map[string][]string{"Content-Type": {"application/json"}})
+	assert.Equal(t, "application/json", c.ContentType())
+
+	c = n.ForRequest(nil)
+	assert.Equal(t, "application/json", c.ContentType())
+}
+
+type greetRequest struct {
+	Name string `json:"name"`
+}
+
+type greetResponse struct {
+	Greeting string `json:"greeting"`
+}
+
+func TestTyped_BadRequest(t *testing.T) {
+	server, err := natsserver.NewServer(&natsserver.Options{DontListen: true})
+	require.NoError(t, err)
+	server.Start()
+	defer server.Shutdown()
+	require.True(t, server.ReadyForConnections(time.Second*5))
+
+	s, err := itsy.Start(itsy.Options{
+		Config:         itsy.Config{Prefix: "test-itsy"},
+		Name:           "itsy-example",
+		ConnectOptions: []nats.Option{nats.InProcessServer(server)},
+	})
+	require.NoError(t, err)
+	defer s.Stop()
+
+	handler := itsy.Typed(func(ctx context.Context, r itsy.Request, in greetRequest) (greetResponse, error) {
+		return greetResponse{Greeting: "hello " + in.Name}, nil
+	}, itsy.JSONCodec{})
+	err = s.Group("", nil).AddEndpoint("greet", handler, nil)
+	require.NoError(t, err)
+
+	nc, err := nats.Connect("", nats.InProcessServer(server))
+	require.NoError(t, err)
+	defer nc.Close()
+
+	msg, err := nc.Request("test-itsy.itsy-example.greet", []byte("not json"), time.Second)
+	require.NoError(t, err)
+
+	assert.Equal(t, "BAD_REQUEST", msg.Header.Get("Nats-Service-Error-Code"))
+}
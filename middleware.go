@@ -0,0 +1,15 @@
+package itsy
+
+// Middleware wraps a HandlerFunc to add cross-cutting behaviour such as
+// recovery, logging or tracing. Middlewares compose like decorators: the
+// first middleware in a chain is the outermost, i.e. chain(h, m1, m2) runs
+// as m1(m2(h)).
+type Middleware func(HandlerFunc) HandlerFunc
+
+// chain applies mws around handler, with mws[0] as the outermost layer.
+func chain(handler HandlerFunc, mws []Middleware) HandlerFunc {
+	for i := len(mws) - 1; i >= 0; i-- {
+		handler = mws[i](handler)
+	}
+	return handler
+}
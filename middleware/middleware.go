@@ -0,0 +1,55 @@
+// Package middleware provides a few first-class [itsy.Middleware]
+// implementations that most services end up needing: panic recovery,
+// request logging and OpenTelemetry tracing.
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/PowerDNS/itsy"
+)
+
+// Recover turns a panic in the wrapped handler into an [itsy.ErrorResponse]
+// with code "PANIC", instead of crashing the service.
+func Recover() itsy.Middleware {
+	return func(next itsy.HandlerFunc) itsy.HandlerFunc {
+		return func(req itsy.Request) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = itsy.Wrap(fmt.Errorf("panic: %v", r), "PANIC")
+				}
+			}()
+			return next(req)
+		}
+	}
+}
+
+// LogRequests logs one structured entry per request, with the subject,
+// latency and error code (if any).
+func LogRequests(logger *slog.Logger) itsy.Middleware {
+	return func(next itsy.HandlerFunc) itsy.HandlerFunc {
+		return func(req itsy.Request) error {
+			start := time.Now()
+			err := next(req)
+
+			attrs := []any{
+				"subject", req.Subject(),
+				"latency", time.Since(start),
+			}
+			if err != nil {
+				code := "ERR"
+				var er itsy.ErrorResponse
+				if errors.As(err, &er) {
+					code = er.Code
+				}
+				logger.Error("handled request", append(attrs, "error_code", code, "err", err)...)
+			} else {
+				logger.Info("handled request", attrs...)
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package middleware_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/PowerDNS/itsy"
+	"github.com/PowerDNS/itsy/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecover(t *testing.T) {
+	handler := middleware.Recover()(func(req itsy.Request) error {
+		panic("boom")
+	})
+
+	err := handler(itsy.Request{})
+	assert.Error(t, err)
+
+	var er itsy.ErrorResponse
+	assert.True(t, errors.As(err, &er))
+	assert.Equal(t, "PANIC", er.Code)
+}
+
+func TestRecover_NoPanic(t *testing.T) {
+	handler := middleware.Recover()(func(req itsy.Request) error {
+		return nil
+	})
+
+	assert.NoError(t, handler(itsy.Request{}))
+}
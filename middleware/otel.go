@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/PowerDNS/itsy"
+	"github.com/nats-io/nats.go/micro"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// OTel starts a server span named after the request subject, extracting the
+// W3C traceparent/tracestate headers from the incoming request and
+// injecting the resulting span context back onto the response headers so
+// the caller can continue the trace. The span context is also attached to
+// the request so handlers can pick it up via [itsy.Request.Context], e.g.
+// to start child spans of their own.
+func OTel(tracer trace.Tracer) itsy.Middleware {
+	prop := otel.GetTextMapPropagator()
+	return func(next itsy.HandlerFunc) itsy.HandlerFunc {
+		return func(req itsy.Request) error {
+			ctx := prop.Extract(context.Background(), headerCarrier(req.Headers()))
+
+			ctx, span := tracer.Start(ctx, req.Subject(), trace.WithSpanKind(trace.SpanKindServer))
+			defer span.End()
+
+			respHeaders := micro.Headers{}
+			prop.Inject(ctx, headerCarrier(respHeaders))
+			req = req.WithRespondOpts(micro.WithHeaders(respHeaders))
+			req = req.WithContext(ctx)
+
+			err := next(req)
+			if err != nil {
+				span.RecordError(err)
+			}
+			return err
+		}
+	}
+}
+
+// headerCarrier adapts micro.Headers to otel's propagation.TextMapCarrier.
+type headerCarrier micro.Headers
+
+func (h headerCarrier) Get(key string) string {
+	vals := h[key]
+	if len(vals) == 0 {
+		return ""
+	}
+	return vals[0]
+}
+
+func (h headerCarrier) Set(key, value string) {
+	h[key] = []string{value}
+}
+
+func (h headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,41 @@
+package itsy
+
+import (
+	"fmt"
+	"reflect"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// ProtoCodec marshals and unmarshals payloads as binary protobuf. Req and
+// Resp types used with this codec must implement [proto.Message].
+type ProtoCodec struct{}
+
+func (ProtoCodec) ContentType() string { return "application/x-protobuf" }
+
+func (ProtoCodec) Marshal(v any) ([]byte, error) {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("itsy: %T does not implement proto.Message", v)
+	}
+	return proto.Marshal(m)
+}
+
+func (ProtoCodec) Unmarshal(data []byte, v any) error {
+	m, ok := v.(proto.Message)
+	if !ok {
+		// Typed passes a pointer to its Req value; when Req is itself a
+		// message pointer, allocate the message and unmarshal into it.
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Ptr {
+			return fmt.Errorf("itsy: %T does not implement proto.Message", v)
+		}
+		if rv.Elem().IsNil() {
+			rv.Elem().Set(reflect.New(rv.Elem().Type().Elem()))
+		}
+		if m, ok = rv.Elem().Interface().(proto.Message); !ok {
+			return fmt.Errorf("itsy: %T does not implement proto.Message", v)
+		}
+	}
+	return proto.Unmarshal(data, m)
+}
@@ -1,6 +1,7 @@
 package itsy
 
 import (
+	"context"
 	"errors"
 
 	"github.com/nats-io/nats.go/micro"
@@ -9,8 +10,23 @@ import (
 // Request describes an Itsy service request.
 // It is modelled on the NATS micro.Request interface
 type Request struct {
-	mr   micro.Request
-	opts []micro.RespondOpt
+	mr  micro.Request
+	ctx context.Context
+	// defaults holds the service's static default response options (e.g.
+	// the Service-ID/-Name/... headers set in Service.start), shared
+	// read-only across every request for the lifetime of the service. It is
+	// always applied last, after opts, so that a shared default header map
+	// is never aliased into a message that something else still writes to -
+	// micro.WithHeaders aliases its map into the message the first time it
+	// runs, and merges into it on every later call.
+	defaults []micro.RespondOpt
+	// opts points at the extra per-request response options accumulated by
+	// middleware for this request, shared by every copy of this Request
+	// derived from the same incoming message, so that
+	// [Request.WithRespondOpts] called by a middleware is visible to
+	// toMicroHandler's own RespondErr call on the original Request, even
+	// though middlewares only ever see (and mutate) a by-value copy.
+	opts *[]micro.RespondOpt
 }
 
 // Reply returns underlying NATS message reply subject.
@@ -18,17 +34,38 @@ func (r Request) Reply() string {
 	return r.mr.Reply()
 }
 
+// Context returns the request's context, e.g. to carry a tracing span
+// started by a middleware such as [middleware.OTel] into the handler. It is
+// never nil, defaulting to context.Background() for a request that no
+// middleware has attached a context to.
+func (r Request) Context() context.Context {
+	if r.ctx == nil {
+		return context.Background()
+	}
+	return r.ctx
+}
+
+// WithContext returns a copy of r carrying ctx, for middlewares that derive
+// a context (e.g. starting a span) to pass it on to the rest of the handler
+// chain.
+func (r Request) WithContext(ctx context.Context) Request {
+	r.ctx = ctx
+	return r
+}
+
 // Respond sends the response for the request.
 // Additional headers can be passed using [WithHeaders] option.
 func (r Request) Respond(msg []byte, opts ...micro.RespondOpt) error {
-	opts = append(opts, r.opts...)
+	opts = append(opts, *r.opts...)
+	opts = append(opts, r.defaults...)
 	return r.mr.Respond(msg, opts...)
 }
 
 // RespondJSON marshals the given response value and responds to the request.
 // Additional headers can be passed using [WithHeaders] option.
 func (r Request) RespondJSON(data any, opts ...micro.RespondOpt) error {
-	opts = append(opts, r.opts...)
+	opts = append(opts, *r.opts...)
+	opts = append(opts, r.defaults...)
 	return r.mr.RespondJSON(data, opts...)
 }
 
@@ -36,7 +73,8 @@ func (r Request) RespondJSON(data any, opts ...micro.RespondOpt) error {
 // A response error should be set containing an error code and description.
 // Optionally, data can be set as response payload.
 func (r Request) Error(code, description string, data []byte, opts ...micro.RespondOpt) error {
-	opts = append(opts, r.opts...)
+	opts = append(opts, *r.opts...)
+	opts = append(opts, r.defaults...)
 	return r.mr.Error(code, description, data, opts...)
 }
 
@@ -58,6 +96,17 @@ func (r Request) Subject() string {
 // Verify that this implements the micro.Request interface
 var _ micro.Request = &Request{}
 
+// WithRespondOpts adds opts to the request's per-request response options, in
+// place, so every subsequent Respond/RespondJSON/RespondErr/Error call made
+// from this request - or any other copy of it, including the one
+// toMicroHandler uses to call RespondErr after a middleware chain returns an
+// error - includes them. Middlewares use this to inject response headers,
+// e.g. a trace context, without handlers having to know about it.
+func (r Request) WithRespondOpts(opts ...micro.RespondOpt) Request {
+	*r.opts = append(*r.opts, opts...)
+	return r
+}
+
 // Extra extensions added by us
 
 // RespondErr provides an easy way to return a Go error as error
@@ -68,7 +117,8 @@ func (r Request) RespondErr(err error, opts ...micro.RespondOpt) error {
 	if errors.As(err, &er) {
 		code = er.Code
 	}
-	opts = append(opts, r.opts...)
+	opts = append(opts, *r.opts...)
+	opts = append(opts, r.defaults...)
 	return r.mr.Error(code, err.Error(), nil, opts...)
 }
 
@@ -0,0 +1,185 @@
+package itsy
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/nats-io/nats.go/micro"
+)
+
+// SchemaSubject is a single subject an endpoint is reachable under, with the
+// topology level it corresponds to.
+type SchemaSubject struct {
+	Subject  string `json:"subject"`
+	Topology string `json:"topology,omitempty"`
+}
+
+// EndpointSchema describes one registered endpoint for the $ITSY.SCHEMA
+// discovery document.
+type EndpointSchema struct {
+	Name           string            `json:"name"`
+	Subjects       []SchemaSubject   `json:"subjects"`
+	RequestSchema  json.RawMessage   `json:"request_schema,omitempty"`
+	ResponseSchema json.RawMessage   `json:"response_schema,omitempty"`
+	Meta           map[string]string `json:"meta,omitempty"`
+}
+
+// SchemaDocument is the payload returned by the $ITSY.SCHEMA endpoint.
+type SchemaDocument struct {
+	Name      string           `json:"name"`
+	Endpoints []EndpointSchema `json:"endpoints"`
+}
+
+// endpointDoc is the internal bookkeeping entry recorded by
+// [Group.AddEndpoint] for every registered endpoint.
+type endpointDoc struct {
+	Name           string
+	Subjects       []SchemaSubject
+	RequestSchema  json.RawMessage
+	ResponseSchema json.RawMessage
+	Meta           map[string]string
+}
+
+// schemaDocument builds the current $ITSY.SCHEMA document from the
+// endpoints registered so far.
+func (s *Service) schemaDocument() SchemaDocument {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc := SchemaDocument{
+		Name:      s.opt.Name,
+		Endpoints: make([]EndpointSchema, 0, len(s.docs)),
+	}
+	for _, d := range s.docs {
+		doc.Endpoints = append(doc.Endpoints, EndpointSchema{
+			Name:           d.Name,
+			Subjects:       d.Subjects,
+			RequestSchema:  d.RequestSchema,
+			ResponseSchema: d.ResponseSchema,
+			Meta:           d.Meta,
+		})
+	}
+	return doc
+}
+
+// addSchemaEndpoint registers the built-in $ITSY.SCHEMA endpoint at
+// "<prefix>.<service>.schema". Unlike regular handlers it is not expanded by
+// topology: it is always reachable directly, regardless of the instance's
+// configured topologies.
+func (s *Service) addSchemaEndpoint() error {
+	g := s.svc.AddGroup(s.basePrefix())
+	return g.AddEndpoint(
+		"schema",
+		micro.HandlerFunc(func(mr micro.Request) {
+			data, err := json.Marshal(s.schemaDocument())
+			if err != nil {
+				_ = mr.Error("ERR", err.Error(), nil, s.ropts...)
+				return
+			}
+			_ = mr.Respond(data, s.ropts...)
+		}),
+		micro.WithEndpointSubject("schema"),
+		micro.WithEndpointMetadata(map[string]string{"topo": ""}),
+	)
+}
+
+// toJSONSchema converts v, as passed to EndpointOptions.RequestSchema or
+// ResponseSchema, into a JSON Schema document. v may be nil, a
+// json.RawMessage (or []byte) holding a document already, or a Go value to
+// reflect a document from.
+func toJSONSchema(v any) (json.RawMessage, error) {
+	switch t := v.(type) {
+	case nil:
+		return nil, nil
+	case json.RawMessage:
+		return t, nil
+	case []byte:
+		return json.RawMessage(t), nil
+	default:
+		return reflectJSONSchema(reflect.TypeOf(v))
+	}
+}
+
+// reflectJSONSchema builds a minimal JSON Schema document for t: enough to
+// tell tooling the shape of a request/response, not a full implementation of
+// the JSON Schema spec (no validation keywords beyond "required").
+func reflectJSONSchema(t reflect.Type) (json.RawMessage, error) {
+	schema, err := reflectType(t)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(schema)
+}
+
+func reflectType(t reflect.Type) (map[string]any, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}, nil
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}, nil
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}, nil
+	case reflect.Slice, reflect.Array:
+		items, err := reflectType(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "array", "items": items}, nil
+	case reflect.Map:
+		values, err := reflectType(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "object", "additionalProperties": values}, nil
+	case reflect.Struct:
+		return reflectStruct(t)
+	default:
+		return nil, fmt.Errorf("itsy: cannot reflect JSON schema for kind %s", t.Kind())
+	}
+}
+
+func reflectStruct(t reflect.Type) (map[string]any, error) {
+	properties := make(map[string]any)
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tag, ok := f.Tag.Lookup("json")
+		name, opts := f.Name, ""
+		if ok {
+			name, opts, _ = strings.Cut(tag, ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+		}
+		prop, err := reflectType(f.Type)
+		if err != nil {
+			return nil, err
+		}
+		properties[name] = prop
+		if !strings.Contains(opts, "omitempty") {
+			required = append(required, name)
+		}
+	}
+	schema := map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema, nil
+}
@@ -0,0 +1,70 @@
+package itsy_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/PowerDNS/itsy"
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type greetReq struct {
+	Name string `json:"name"`
+}
+
+type greetResp struct {
+	Greeting string `json:"greeting"`
+}
+
+func TestSchemaEndpoint(t *testing.T) {
+	server, err := natsserver.NewServer(&natsserver.Options{DontListen: true})
+	require.NoError(t, err)
+	server.Start()
+	defer server.Shutdown()
+	require.True(t, server.ReadyForConnections(time.Second*5))
+
+	s, err := itsy.Start(itsy.Options{
+		Config: itsy.Config{
+			Prefix:     "test-itsy",
+			Topologies: []string{"eu.nl.ams"},
+		},
+		Name:           "itsy-example",
+		ConnectOptions: []nats.Option{nats.InProcessServer(server)},
+	})
+	require.NoError(t, err)
+	defer s.Stop()
+
+	err = s.Group("", nil).AddEndpoint("greet", func(req itsy.Request) error {
+		return req.Respond(req.Data())
+	}, &itsy.EndpointOptions{
+		RequestSchema:  greetReq{},
+		ResponseSchema: greetResp{},
+	})
+	require.NoError(t, err)
+
+	nc, err := nats.Connect("", nats.InProcessServer(server))
+	require.NoError(t, err)
+	defer nc.Close()
+
+	msg, err := nc.Request("test-itsy.itsy-example.schema", nil, time.Second)
+	require.NoError(t, err)
+
+	var doc itsy.SchemaDocument
+	require.NoError(t, json.Unmarshal(msg.Data, &doc))
+	assert.Equal(t, "itsy-example", doc.Name)
+
+	var found *itsy.EndpointSchema
+	for i := range doc.Endpoints {
+		if doc.Endpoints[i].Name == "test-itsy.itsy-example.greet" {
+			found = &doc.Endpoints[i]
+		}
+	}
+	if assert.NotNil(t, found) {
+		assert.NotEmpty(t, found.RequestSchema)
+		assert.NotEmpty(t, found.ResponseSchema)
+	}
+}
@@ -1,12 +1,14 @@
 package itsy
 
 import (
+	"encoding/json"
 	"errors"
 	"log/slog"
 	"os"
 	"slices"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/nats-io/nats.go"
 	"github.com/nats-io/nats.go/micro"
@@ -22,6 +24,12 @@ type Options struct {
 	SubjectName    string // Name part as used in subject if different from Name
 	Description    string // Service description
 	ConnectOptions []nats.Option
+	Middlewares    []Middleware // applied to every handler, outermost first
+
+	// MetricsRegisterer records per-endpoint request metrics. Defaults to an
+	// internal bounded in-memory [Stats] registry, which also backs
+	// $SRV.STATS and [Service.PrometheusHandler].
+	MetricsRegisterer MetricsRegisterer
 }
 
 // Start starts a new Itsy NATS Service in the background.
@@ -36,9 +44,11 @@ func Start(opt Options) (*Service, error) {
 	}
 	opt.Config = opt.Config.AddEnviron()
 	svc := &Service{
-		opt:  opt,
-		conf: opt.Config,
-		l:    opt.Logger,
+		opt:            opt,
+		conf:           opt.Config,
+		l:              opt.Logger,
+		statsNames:     make(map[string]string),
+		statsCanonical: make(map[string]bool),
 	}
 	err := svc.start()
 	if err != nil {
@@ -51,9 +61,37 @@ func Start(opt Options) (*Service, error) {
 type HandlerFunc func(req Request) error
 
 // HandlerOptions are options that influence how a handler is registered.
-// This struct is currently empty, but allows for future expansion.
 type HandlerOptions struct {
+	Global      bool         // Do not scope the subject with the service name
+	Middlewares []Middleware // applied to this handler only, after the service-wide ones
+}
+
+// GroupOptions are options that influence how a [Group] is registered.
+type GroupOptions struct {
 	Global bool // Do not scope the subject with the service name
+
+	// Topology overrides the service's configured topology for endpoints
+	// registered in this group. Leave nil to inherit the service topology.
+	Topology []string
+
+	// IDOnly registers endpoints in this group only under "id.<svcID>",
+	// skipping the "all"/"any" fan-out. This is useful for admin endpoints
+	// that should never be reached through broadcast or load-balanced calls.
+	IDOnly bool
+}
+
+// EndpointOptions are options that influence how an endpoint within a
+// [Group] is registered.
+type EndpointOptions struct {
+	QueueGroup  string            // overrides the computed queue group
+	Meta        map[string]string // extra endpoint metadata, merged alongside the "topo" key
+	Middlewares []Middleware      // applied to this endpoint only, after the service-wide ones
+
+	// RequestSchema and ResponseSchema document the endpoint's payloads for
+	// the $ITSY.SCHEMA discovery endpoint. Each may be a json.RawMessage
+	// holding a JSON Schema document, or a Go value to be reflected into one.
+	RequestSchema  any
+	ResponseSchema any
 }
 
 // Service is the main object that describes the microservice
@@ -66,6 +104,15 @@ type Service struct {
 	svc   micro.Service
 	ropts []micro.RespondOpt
 	topo  []string
+	mw    []Middleware
+
+	mu             sync.Mutex
+	docs           []endpointDoc
+	statsNames     map[string]string // full subject -> base endpoint name, for endpointStats
+	statsCanonical map[string]bool   // full subject -> whether it's the one subject $SRV.STATS reports the aggregate under
+
+	metrics MetricsRegisterer
+	stats   *Stats // non-nil unless a custom MetricsRegisterer was configured
 }
 
 // Conn returns the underlying NATS connection
@@ -78,53 +125,180 @@ func (s *Service) ID() string {
 	return s.svc.Info().ID
 }
 
+// basePrefix returns the subject prefix scoped to this service, e.g.
+// "svc.itsy-example".
+func (s *Service) basePrefix() string {
+	prefix := s.conf.Prefix
+	if prefix == "" {
+		prefix = "svc"
+	}
+	svcName := s.opt.Name
+	if s.opt.SubjectName != "" {
+		svcName = s.opt.SubjectName
+	}
+	return prefix + "." + svcName
+}
+
 // AddHandler registers a HandlerFunc.
 // It returns an error if the name or an option is valid.
 func (s *Service) AddHandler(name string, handler HandlerFunc, opts *HandlerOptions) error {
+	var groupOpts GroupOptions
+	var endpointOpts *EndpointOptions
+	if opts != nil {
+		groupOpts.Global = opts.Global
+		endpointOpts = &EndpointOptions{Middlewares: opts.Middlewares}
+	}
+	return s.Group("", &groupOpts).AddEndpoint(name, handler, endpointOpts)
+}
+
+// MustAddHandler registers a HandlerFunc, and panics if anything goes wrong.
+// Nothing will go wrong if the name and options are valid.
+func (s *Service) MustAddHandler(name string, handler HandlerFunc, opts *HandlerOptions) {
+	if err := s.AddHandler(name, handler, opts); err != nil {
+		panic(err)
+	}
+}
+
+// Group is a named collection of endpoints that share a subject prefix,
+// topology and registration behaviour. Create one with [Service.Group].
+type Group struct {
+	s      *Service
+	prefix string
+	topo   []string
+	idOnly bool
+}
+
+// Group returns a [Group] with its own subject path segment, rooted under
+// the service's prefix unless opts.Global is set. Passing an empty name
+// returns a group at the service's own root, which is what [Service.AddHandler]
+// uses internally.
+func (s *Service) Group(name string, opts *GroupOptions) *Group {
 	prefix := s.conf.Prefix
 	if prefix == "" {
 		prefix = "svc"
 	}
-	globalName := opts != nil && opts.Global
-	if !globalName {
-		svcName := s.opt.Name
-		if s.opt.SubjectName != "" {
-			svcName = s.opt.SubjectName
+	global := opts != nil && opts.Global
+	if !global {
+		prefix = s.basePrefix()
+	}
+	if name != "" {
+		prefix += "." + name
+	}
+
+	topo := s.topo
+	idOnly := false
+	if opts != nil {
+		if opts.Topology != nil {
+			topo = slices.Clone(opts.Topology)
+			sort.Strings(topo)
 		}
-		prefix += "." + svcName
+		idOnly = opts.IDOnly
 	}
+
+	return &Group{s: s, prefix: prefix, topo: topo, idOnly: idOnly}
+}
+
+// AddEndpoint registers a HandlerFunc under this group.
+// It returns an error if the name or an option is invalid.
+func (g *Group) AddEndpoint(name string, handler HandlerFunc, opts *EndpointOptions) error {
+	s := g.s
 	svcID := s.ID()
 
-	g := s.svc.AddGroup(prefix)
-	for _, name := range ExpandTopology(name, s.topo, svcID) {
-		s.l.Info("Adding NATS endpoint", "subject", prefix+"."+name.Full)
-		q := "q"
-		if name.All {
-			// Using unique queue groups ensures that all instances respond
-			q = "id." + svcID
+	meta := map[string]string{}
+	queueOverride := ""
+	var endpointMW []Middleware
+	var reqSchema, respSchema json.RawMessage
+	if opts != nil {
+		for k, v := range opts.Meta {
+			meta[k] = v
 		}
-		err := g.AddEndpoint(
-			strings.Replace(name.Full, ".", "-", -1),
-			toMicroHandler(handler, s.ropts),
-			micro.WithEndpointSubject(name.Full),
-			micro.WithEndpointQueueGroup(q),
-			micro.WithEndpointMetadata(map[string]string{"topo": name.Topo}),
-		)
-		if err != nil {
+		queueOverride = opts.QueueGroup
+		endpointMW = opts.Middlewares
+
+		var err error
+		if reqSchema, err = toJSONSchema(opts.RequestSchema); err != nil {
 			return err
 		}
+		if respSchema, err = toJSONSchema(opts.ResponseSchema); err != nil {
+			return err
+		}
+	}
+	baseName := join(g.prefix, name)
+	mws := append([]Middleware{s.statsMiddleware(baseName)}, slices.Clone(s.mw)...)
+	handler = chain(handler, append(mws, endpointMW...))
+
+	mg := s.svc.AddGroup(g.prefix)
+
+	var names NameList
+	if g.idOnly {
+		names = NameList{newName(name, "id", svcID, false)}
+	} else {
+		names = ExpandTopology(name, g.topo, svcID)
 	}
+
+	doc := endpointDoc{
+		Name:           baseName,
+		RequestSchema:  reqSchema,
+		ResponseSchema: respSchema,
+		Meta:           meta,
+	}
+	s.mu.Lock()
+	for i, n := range names {
+		if err := g.addMicroEndpoint(mg, n, handler, meta, queueOverride); err != nil {
+			s.mu.Unlock()
+			return err
+		}
+		subject := g.prefix + "." + n.Full
+		doc.Subjects = append(doc.Subjects, SchemaSubject{Subject: subject, Topology: n.Topo})
+		s.statsNames[subject] = baseName
+		// $SRV.STATS reports one entry per expanded subject, but they all
+		// share the same underlying counters; only report the aggregate
+		// under the first one (names is sorted, so this is the bare,
+		// un-scoped subject whenever one exists), so summing across
+		// $SRV.STATS entries doesn't overcount by the topology fan-out
+		// factor.
+		s.statsCanonical[subject] = i == 0
+	}
+	s.docs = append(s.docs, doc)
+	s.mu.Unlock()
 	return nil
 }
 
-// MustAddHandler registers a HandlerFunc, and panics if anything goes wrong.
-// Nothing will go wrong if the name and options are valid.
-func (s *Service) MustAddHandler(name string, handler HandlerFunc, opts *HandlerOptions) {
-	if err := s.AddHandler(name, handler, opts); err != nil {
+// MustAddEndpoint registers a HandlerFunc under this group, and panics if
+// anything goes wrong. Nothing will go wrong if the name and options are valid.
+func (g *Group) MustAddEndpoint(name string, handler HandlerFunc, opts *EndpointOptions) {
+	if err := g.AddEndpoint(name, handler, opts); err != nil {
 		panic(err)
 	}
 }
 
+func (g *Group) addMicroEndpoint(mg micro.Group, n Name, handler HandlerFunc, extraMeta map[string]string, queueOverride string) error {
+	s := g.s
+	s.l.Info("Adding NATS endpoint", "subject", g.prefix+"."+n.Full)
+
+	q := queueOverride
+	if q == "" {
+		q = "q"
+		if n.All {
+			// Using unique queue groups ensures that all instances respond
+			q = "id." + s.ID()
+		}
+	}
+
+	meta := map[string]string{"topo": n.Topo}
+	for k, v := range extraMeta {
+		meta[k] = v
+	}
+
+	return mg.AddEndpoint(
+		strings.Replace(n.Full, ".", "-", -1),
+		toMicroHandler(handler, s.ropts),
+		micro.WithEndpointSubject(n.Full),
+		micro.WithEndpointQueueGroup(q),
+		micro.WithEndpointMetadata(meta),
+	)
+}
+
 // Stop stops the NATS service. Once stopped, the object cannot be used again.
 func (s *Service) Stop() {
 	err := s.nc.Drain()
@@ -139,8 +313,16 @@ func (s *Service) start() error {
 	topo := slices.Clone(s.conf.Topologies)
 	sort.Strings(topo)
 	s.topo = topo
+	s.mw = s.opt.Middlewares
 	topoString := strings.Join(topo, " ")
 
+	if s.opt.MetricsRegisterer != nil {
+		s.metrics = s.opt.MetricsRegisterer
+	} else {
+		s.stats = NewStats()
+		s.metrics = s.stats
+	}
+
 	// Create a NATS connection. This will automatically reconnect when needed.
 	// All of these can be overridden with custom ConnectOptions.
 	connectOpts := []nats.Option{
@@ -196,6 +378,9 @@ func (s *Service) start() error {
 		Version:     versionSemVer,
 		Description: s.opt.Description,
 		Metadata:    meta,
+		StatsHandler: func(ep *micro.Endpoint) any {
+			return s.endpointStats(ep)
+		},
 	}
 	svc, err := micro.AddService(nc, svcConfig)
 	if err != nil {
@@ -218,16 +403,22 @@ func (s *Service) start() error {
 	withDefaultHeaders := micro.WithHeaders(defaultHeaders)
 	s.ropts = []micro.RespondOpt{withDefaultHeaders}
 
+	if err := s.addSchemaEndpoint(); err != nil {
+		return err
+	}
+
 	s.l.Info("NATS services registered", "id", svcID, "topologies", topoString)
 	return nil
 }
 
 // toMicroHandler converts our HandlerFunc to a micro.HandlerFunc
-func toMicroHandler(handler HandlerFunc, ropts []micro.RespondOpt) micro.HandlerFunc {
+func toMicroHandler(handler HandlerFunc, defaults []micro.RespondOpt) micro.HandlerFunc {
 	return func(mr micro.Request) {
+		var opts []micro.RespondOpt
 		req := Request{
-			mr:   mr,
-			opts: ropts,
+			mr:       mr,
+			defaults: defaults,
+			opts:     &opts,
 		}
 		if err := handler(req); err != nil {
 			_ = req.RespondErr(err)
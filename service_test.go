@@ -62,6 +62,56 @@ func ExampleService() {
 	// Done
 }
 
+func ExampleService_Group() {
+	// Start an in-process NATS server for this example
+	server, err := natsserver.NewServer(&natsserver.Options{
+		DontListen: true,
+	})
+	check(err)
+	server.Start()
+	defer server.Shutdown()
+	if !server.ReadyForConnections(time.Second * 5) {
+		panic("NATS server didn't start")
+	}
+
+	conf := itsy.Config{
+		Prefix: "test-itsy",
+		Topologies: []string{
+			"eu.nl.ams",
+		},
+	}
+	s, err := itsy.Start(itsy.Options{
+		Config:        conf,
+		VersionSemVer: "0.0.1",
+		Name:          "itsy-example",
+		Description:   "An example service",
+		ConnectOptions: []nats.Option{
+			nats.InProcessServer(server),
+		},
+	})
+	check(err)
+	defer s.Stop()
+
+	// Admin endpoints are only reachable by pinning to a specific instance,
+	// never through the "all"/"any" fan-out used for regular data endpoints.
+	admin := s.Group("admin", &itsy.GroupOptions{IDOnly: true})
+	admin.MustAddEndpoint("drain", func(req itsy.Request) error {
+		return req.Respond([]byte("draining"))
+	}, nil)
+
+	nc, err := nats.Connect("", nats.InProcessServer(server))
+	check(err)
+	defer nc.Close()
+
+	subject := fmt.Sprintf("test-itsy.itsy-example.admin.drain.id.%s", s.ID())
+	msg, err := nc.Request(subject, nil, time.Second)
+	check(err)
+	fmt.Println("Received:", string(msg.Data))
+
+	// Output:
+	// Received: draining
+}
+
 func check(err error) {
 	if err != nil {
 		panic(err)
@@ -0,0 +1,289 @@
+package itsy
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go/micro"
+)
+
+// latencyBucketsMs are the upper bounds (in milliseconds) of the fixed
+// latency histogram buckets. Using a fixed set of buckets, rather than
+// storing every sample, bounds memory use regardless of traffic volume.
+var latencyBucketsMs = []float64{1, 2, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
+// EndpointStats is a point-in-time snapshot of the counters recorded for one
+// endpoint, keyed by the base handler name rather than the expanded
+// topology subject, so e.g. "all.eu.nl.ams" and "any.eu" collapse into a
+// single entry.
+type EndpointStats struct {
+	Requests          uint64  `json:"requests"`
+	Errors            uint64  `json:"errors"`
+	LatencySumSeconds float64 `json:"latency_sum_seconds"`
+
+	// LatencyBucketsMs and LatencyBucketCounts are parallel slices of
+	// cumulative histogram bucket upper bounds and counts; the final count
+	// is the +Inf bucket.
+	LatencyBucketsMs    []float64 `json:"latency_buckets_ms"`
+	LatencyBucketCounts []uint64  `json:"latency_bucket_counts"`
+
+	// Quantiles holds p50/p90/p99 latency estimates in milliseconds,
+	// linearly interpolated from the bucket histogram above. Bucketed
+	// interpolation trades exactness for the bounded memory use that ruled
+	// out a streaming digest; estimates are only as precise as the bucket
+	// containing the target rank.
+	Quantiles map[string]float64 `json:"quantiles,omitempty"`
+}
+
+// quantiles are the percentiles reported in EndpointStats.Quantiles.
+var quantiles = []float64{0.5, 0.9, 0.99}
+
+// estimateQuantile linearly interpolates the q-th quantile (0..1) from a
+// cumulative histogram, assuming samples are spread uniformly within each
+// bucket. buckets and counts must be parallel slices as in
+// EndpointStats.LatencyBucketsMs/LatencyBucketCounts, with counts
+// cumulative and the final entry the +Inf bucket. Returns 0 if there are no
+// samples.
+func estimateQuantile(buckets []float64, counts []uint64, q float64) float64 {
+	total := counts[len(counts)-1]
+	if total == 0 {
+		return 0
+	}
+	target := q * float64(total)
+
+	lowerBound, lowerCount := 0.0, uint64(0)
+	for i, count := range counts {
+		if float64(count) >= target {
+			if i == len(buckets) {
+				// Target rank falls in the +Inf bucket; there's no upper
+				// bound to interpolate against, so report the last finite
+				// bucket's upper bound.
+				return buckets[len(buckets)-1]
+			}
+			upperBound := buckets[i]
+			if count == lowerCount {
+				return upperBound
+			}
+			frac := (target - float64(lowerCount)) / float64(count-lowerCount)
+			return lowerBound + frac*(upperBound-lowerBound)
+		}
+		lowerBound, lowerCount = buckets[i], count
+	}
+	return buckets[len(buckets)-1]
+}
+
+// MetricsRegisterer records per-endpoint request metrics. The default,
+// [Stats], is an in-memory bounded-histogram registry; set
+// Options.MetricsRegisterer to route observations to an existing metrics
+// system instead.
+type MetricsRegisterer interface {
+	Observe(endpoint string, duration time.Duration, errCode string)
+}
+
+// endpointCounters are the mutable counters backing one EndpointStats.
+type endpointCounters struct {
+	mu       sync.Mutex
+	requests uint64
+	errors   uint64
+	sumSec   float64
+	buckets  []uint64 // len(latencyBucketsMs)+1, cumulative, last is +Inf
+}
+
+func newEndpointCounters() *endpointCounters {
+	return &endpointCounters{buckets: make([]uint64, len(latencyBucketsMs)+1)}
+}
+
+func (c *endpointCounters) observe(d time.Duration, errCode string) {
+	ms := float64(d) / float64(time.Millisecond)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests++
+	if errCode != "" {
+		c.errors++
+	}
+	c.sumSec += d.Seconds()
+
+	idx := len(latencyBucketsMs)
+	for i, le := range latencyBucketsMs {
+		if ms <= le {
+			idx = i
+			break
+		}
+	}
+	for i := idx; i < len(c.buckets); i++ {
+		c.buckets[i]++
+	}
+}
+
+func (c *endpointCounters) snapshot() EndpointStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	q := make(map[string]float64, len(quantiles))
+	for _, p := range quantiles {
+		q[strconv.FormatFloat(p, 'f', -1, 64)] = estimateQuantile(latencyBucketsMs, c.buckets, p)
+	}
+
+	return EndpointStats{
+		Requests:            c.requests,
+		Errors:              c.errors,
+		LatencySumSeconds:   c.sumSec,
+		LatencyBucketsMs:    append([]float64(nil), latencyBucketsMs...),
+		LatencyBucketCounts: append([]uint64(nil), c.buckets...),
+		Quantiles:           q,
+	}
+}
+
+// Stats is the built-in [MetricsRegisterer]. It backs both the $SRV.STATS
+// "data" field and [Service.PrometheusHandler].
+type Stats struct {
+	mu        sync.Mutex
+	endpoints map[string]*endpointCounters
+}
+
+// NewStats returns an empty Stats registry.
+func NewStats() *Stats {
+	return &Stats{endpoints: make(map[string]*endpointCounters)}
+}
+
+func (s *Stats) counters(endpoint string) *endpointCounters {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.endpoints[endpoint]
+	if !ok {
+		c = newEndpointCounters()
+		s.endpoints[endpoint] = c
+	}
+	return c
+}
+
+// Observe implements [MetricsRegisterer].
+func (s *Stats) Observe(endpoint string, duration time.Duration, errCode string) {
+	s.counters(endpoint).observe(duration, errCode)
+}
+
+// Snapshot returns a point-in-time copy of every endpoint's counters.
+func (s *Stats) Snapshot() map[string]EndpointStats {
+	s.mu.Lock()
+	names := make([]string, 0, len(s.endpoints))
+	counters := make([]*endpointCounters, 0, len(s.endpoints))
+	for name, c := range s.endpoints {
+		names = append(names, name)
+		counters = append(counters, c)
+	}
+	s.mu.Unlock()
+
+	snapshot := make(map[string]EndpointStats, len(names))
+	for i, name := range names {
+		snapshot[name] = counters[i].snapshot()
+	}
+	return snapshot
+}
+
+// ServeHTTP renders the current stats in Prometheus text exposition format.
+func (s *Stats) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	snapshot := s.Snapshot()
+
+	names := make([]string, 0, len(snapshot))
+	for name := range snapshot {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	fmt.Fprintln(w, "# HELP itsy_requests_total Total requests handled per endpoint.")
+	fmt.Fprintln(w, "# TYPE itsy_requests_total counter")
+	for _, name := range names {
+		fmt.Fprintf(w, "itsy_requests_total{endpoint=%q} %d\n", name, snapshot[name].Requests)
+	}
+
+	fmt.Fprintln(w, "# HELP itsy_errors_total Total error responses per endpoint.")
+	fmt.Fprintln(w, "# TYPE itsy_errors_total counter")
+	for _, name := range names {
+		fmt.Fprintf(w, "itsy_errors_total{endpoint=%q} %d\n", name, snapshot[name].Errors)
+	}
+
+	fmt.Fprintln(w, "# HELP itsy_request_duration_seconds Request latency per endpoint.")
+	fmt.Fprintln(w, "# TYPE itsy_request_duration_seconds histogram")
+	for _, name := range names {
+		st := snapshot[name]
+		for i, le := range st.LatencyBucketsMs {
+			fmt.Fprintf(w, "itsy_request_duration_seconds_bucket{endpoint=%q,le=%q} %d\n", name, formatSeconds(le/1000), st.LatencyBucketCounts[i])
+		}
+		fmt.Fprintf(w, "itsy_request_duration_seconds_bucket{endpoint=%q,le=\"+Inf\"} %d\n", name, st.LatencyBucketCounts[len(st.LatencyBucketCounts)-1])
+		fmt.Fprintf(w, "itsy_request_duration_seconds_sum{endpoint=%q} %s\n", name, formatSeconds(st.LatencySumSeconds))
+		fmt.Fprintf(w, "itsy_request_duration_seconds_count{endpoint=%q} %d\n", name, st.Requests)
+	}
+}
+
+func formatSeconds(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// statsMiddleware records request count, error count and latency for name,
+// the endpoint's base (pre-topology-expansion) name, on every call.
+func (s *Service) statsMiddleware(name string) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(req Request) error {
+			start := time.Now()
+			err := next(req)
+			s.metrics.Observe(name, time.Since(start), errCodeOf(err))
+			return err
+		}
+	}
+}
+
+func errCodeOf(err error) string {
+	if err == nil {
+		return ""
+	}
+	var er ErrorResponse
+	if errors.As(err, &er) {
+		return er.Code
+	}
+	return "ERR"
+}
+
+// endpointStats looks up the base endpoint name for ep.Subject and returns
+// its current EndpointStats, for use as a micro.Config.StatsHandler. It
+// returns nil for endpoints we don't track (the $ITSY.SCHEMA endpoint, or
+// any endpoint when Options.MetricsRegisterer is set, even to a *Stats), and
+// for every expanded topology subject of an endpoint except
+// its canonical one, since micro calls this once per registered subject but
+// they all share the same underlying counters - reporting the aggregate
+// under all of them would make it look like $SRV.STATS has requests times
+// the topology fan-out factor.
+func (s *Service) endpointStats(ep *micro.Endpoint) any {
+	if s.stats == nil {
+		return nil
+	}
+	s.mu.Lock()
+	name, ok := s.statsNames[ep.Subject]
+	canonical := s.statsCanonical[ep.Subject]
+	s.mu.Unlock()
+	if !ok || !canonical {
+		return nil
+	}
+	return s.stats.counters(name).snapshot()
+}
+
+// PrometheusHandler serves the same per-endpoint metrics as $SRV.STATS in
+// Prometheus text exposition format, for services that are scraped over
+// HTTP. It only has data to serve when using the default MetricsRegisterer;
+// if Options.MetricsRegisterer was set to a custom implementation, it serves
+// 501 Not Implemented instead.
+func (s *Service) PrometheusHandler() http.Handler {
+	if s.stats == nil {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "prometheus metrics are unavailable with a custom MetricsRegisterer", http.StatusNotImplemented)
+		})
+	}
+	return s.stats
+}
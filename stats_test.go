@@ -0,0 +1,33 @@
+package itsy_test
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/PowerDNS/itsy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats_ObserveAndSnapshot(t *testing.T) {
+	stats := itsy.NewStats()
+	stats.Observe("echo", 5*time.Millisecond, "")
+	stats.Observe("echo", 15*time.Millisecond, "BAD_REQUEST")
+
+	snap := stats.Snapshot()["echo"]
+	assert.Equal(t, uint64(2), snap.Requests)
+	assert.Equal(t, uint64(1), snap.Errors)
+	assert.Positive(t, snap.LatencySumSeconds)
+	assert.Positive(t, snap.Quantiles["0.5"])
+	assert.Positive(t, snap.Quantiles["0.99"])
+}
+
+func TestStats_ServeHTTP(t *testing.T) {
+	stats := itsy.NewStats()
+	stats.Observe("echo", 5*time.Millisecond, "")
+
+	rec := httptest.NewRecorder()
+	stats.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	assert.Contains(t, rec.Body.String(), `itsy_requests_total{endpoint="echo"} 1`)
+}